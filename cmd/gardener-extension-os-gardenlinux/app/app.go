@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package app wires up the controller-runtime manager and controllers that make up the
+// gardener-extension-os-gardenlinux binary.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	extensionscmdcontroller "github.com/gardener/gardener/extensions/pkg/controller/cmd"
+	"github.com/spf13/cobra"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/controller/operatingsystemconfig"
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/features"
+)
+
+// Name is the name of the gardener-extension-os-gardenlinux binary.
+const Name = "gardener-extension-os-gardenlinux"
+
+// Options contains the flag-backed options of the gardener-extension-os-gardenlinux binary.
+type Options struct {
+	generalOptions    *extensionscmdcontroller.GeneralOptions
+	restOptions       *extensionscmdcontroller.RESTOptions
+	managerOptions    *extensionscmdcontroller.ManagerOptions
+	controllerOptions *extensionscmdcontroller.ControllerOptions
+	configOptions     *ConfigOptions
+	optionAggregator  extensionscmdcontroller.OptionAggregator
+}
+
+// NewOptions creates a new Options instance.
+func NewOptions() *Options {
+	options := &Options{
+		generalOptions:    &extensionscmdcontroller.GeneralOptions{},
+		restOptions:       &extensionscmdcontroller.RESTOptions{},
+		managerOptions:    &extensionscmdcontroller.ManagerOptions{},
+		controllerOptions: &extensionscmdcontroller.ControllerOptions{MaxConcurrentReconciles: 5},
+		configOptions:     &ConfigOptions{},
+	}
+
+	options.optionAggregator = extensionscmdcontroller.NewOptionAggregator(
+		options.generalOptions,
+		options.restOptions,
+		options.managerOptions,
+		options.controllerOptions,
+		options.configOptions,
+		extensionscmdcontroller.PrefixOption("featuregates-", features.DefaultFeatureGate),
+	)
+
+	return options
+}
+
+// NewControllerManagerCommand creates the `gardener-extension-os-gardenlinux` cobra command that starts
+// the controller-manager.
+func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
+	options := NewOptions()
+
+	cmd := &cobra.Command{
+		Use:   Name,
+		Short: "Garden Linux Operating System Extension",
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.optionAggregator.Complete(); err != nil {
+				return fmt.Errorf("error completing options: %w", err)
+			}
+
+			mgr, err := options.managerOptions.Completed().Manager(options.restOptions.Completed().Config)
+			if err != nil {
+				return fmt.Errorf("could not instantiate manager: %w", err)
+			}
+
+			if err := operatingsystemconfig.AddToManager(ctx, mgr, *options.configOptions.Completed()); err != nil {
+				return fmt.Errorf("could not add operatingsystemconfig controller to manager: %w", err)
+			}
+
+			return mgr.Start(ctx)
+		},
+	}
+
+	// The feature gates must be registered before the `--featuregates-*` flags are added below, since
+	// pflag parses those flags (and thus validates gate names against the registry) before RunE runs.
+	utilruntime.Must(features.RegisterFeatureGates())
+
+	options.optionAggregator.AddFlags(cmd.Flags())
+
+	return cmd
+}
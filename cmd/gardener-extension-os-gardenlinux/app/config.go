@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha1 "github.com/gardener/gardener-extension-os-gardenlinux/pkg/apis/config/v1alpha1"
+)
+
+// ConfigOptions loads the ControllerConfiguration from a config file passed via `--config`.
+type ConfigOptions struct {
+	ConfigFilePath string
+
+	config *configv1alpha1.ControllerConfiguration
+}
+
+// AddFlags implements extensionscmdcontroller.Option.
+func (o *ConfigOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ConfigFilePath, "config", "", "path to the controller configuration file")
+}
+
+// Complete implements extensionscmdcontroller.Option.
+func (o *ConfigOptions) Complete() error {
+	config := &configv1alpha1.ControllerConfiguration{}
+
+	if o.ConfigFilePath != "" {
+		data, err := os.ReadFile(o.ConfigFilePath) // #nosec G304 -- path is operator-provided via flag
+		if err != nil {
+			return fmt.Errorf("could not read config file %s: %w", o.ConfigFilePath, err)
+		}
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return fmt.Errorf("could not decode config file %s: %w", o.ConfigFilePath, err)
+		}
+	}
+
+	configv1alpha1.SetDefaults_ControllerConfiguration(config)
+	o.config = config
+
+	return nil
+}
+
+// Completed returns the loaded, defaulted ControllerConfiguration.
+func (o *ConfigOptions) Completed() *configv1alpha1.ControllerConfiguration {
+	return o.config
+}
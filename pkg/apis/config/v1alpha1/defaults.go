@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// DefaultHealthCheckSettleWindow is the default value for InPlaceUpdateConfiguration.HealthCheckSettleWindow.
+var DefaultHealthCheckSettleWindow = metav1.Duration{Duration: 5 * time.Minute}
+
+// DefaultMaxRetries is the default value for InPlaceUpdateConfiguration.MaxRetries.
+const DefaultMaxRetries int32 = 10
+
+// SetDefaults_ControllerConfiguration sets the defaults for the ControllerConfiguration.
+func SetDefaults_ControllerConfiguration(obj *ControllerConfiguration) {
+	if obj.InPlaceUpdate == nil {
+		obj.InPlaceUpdate = &InPlaceUpdateConfiguration{}
+	}
+	if obj.InPlaceUpdate.HealthCheckSettleWindow == nil {
+		obj.InPlaceUpdate.HealthCheckSettleWindow = ptr.To(DefaultHealthCheckSettleWindow)
+	}
+	if obj.InPlaceUpdate.MaxRetries == nil {
+		obj.InPlaceUpdate.MaxRetries = ptr.To(DefaultMaxRetries)
+	}
+}
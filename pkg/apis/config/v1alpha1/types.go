@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package v1alpha1 contains the component configuration for the gardener-extension-os-gardenlinux.
+//
+// +k8s:deepcopy-gen=package
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ControllerConfiguration defines the configuration for the gardener-extension-os-gardenlinux.
+type ControllerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// InPlaceUpdate contains the configuration for the automatic rollback of in-place operating system
+	// updates.
+	// +optional
+	InPlaceUpdate *InPlaceUpdateConfiguration `json:"inPlaceUpdate,omitempty"`
+}
+
+// InPlaceUpdateConfiguration contains the configuration for the automatic rollback of in-place
+// operating system updates.
+type InPlaceUpdateConfiguration struct {
+	// HealthCheckSettleWindow is the duration the gardenlinux-update-healthcheck.service waits for
+	// kubelet.service to become ready after an in-place update before giving up and rolling back.
+	// Defaults to 5m.
+	// +optional
+	HealthCheckSettleWindow *metav1.Duration `json:"healthCheckSettleWindow,omitempty"`
+	// MaxRetries is the number of times the gardenlinux-update-healthcheck.service polls for
+	// kubelet.service to become ready, spaced evenly across HealthCheckSettleWindow (at least one second
+	// apart), before rolling back. Defaults to 10.
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+}
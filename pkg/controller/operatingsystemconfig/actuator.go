@@ -9,6 +9,7 @@ import (
 	_ "embed"
 	"fmt"
 	"path/filepath"
+	"strconv"
 
 	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
@@ -17,19 +18,25 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/yaml"
 
+	configv1alpha1 "github.com/gardener/gardener-extension-os-gardenlinux/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/features"
 	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/gardenlinux"
-	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/memoryone"
 )
 
 type actuator struct {
 	client client.Client
+	config configv1alpha1.ControllerConfiguration
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled OperatingSystemConfig resources.
-func NewActuator(mgr manager.Manager) operatingsystemconfig.Actuator {
+func NewActuator(mgr manager.Manager, config configv1alpha1.ControllerConfiguration) operatingsystemconfig.Actuator {
+	configv1alpha1.SetDefaults_ControllerConfiguration(&config)
+
 	return &actuator{
 		client: mgr.GetClient(),
+		config: config,
 	}
 }
 
@@ -65,6 +72,15 @@ func (a *actuator) Restore(ctx context.Context, log logr.Logger, osc *extensions
 }
 
 func (a *actuator) handleProvisionOSC(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) (string, error) {
+	if features.DefaultFeatureGate.Enabled(features.UseGardenerNodeAgent) {
+		return a.handleProvisionOSCGardenerNodeAgent(ctx, osc)
+	}
+
+	runtime, err := gardenlinux.ContainerRuntimeForOSC(osc)
+	if err != nil {
+		return "", err
+	}
+
 	writeFilesToDiskScript, err := operatingsystemconfig.FilesToDiskScript(ctx, a.client, osc.Namespace, osc.Spec.Files)
 	if err != nil {
 		return "", err
@@ -72,75 +88,80 @@ func (a *actuator) handleProvisionOSC(ctx context.Context, osc *extensionsv1alph
 	writeUnitsToDiskScript := operatingsystemconfig.UnitsToDiskScript(osc.Spec.Units)
 
 	script := `#!/bin/bash
-if [ ! -s /etc/containerd/config.toml ]; then
-  mkdir -p /etc/containerd/
-  containerd config default > /etc/containerd/config.toml
-  chmod 0644 /etc/containerd/config.toml
-fi
-
-mkdir -p /etc/systemd/system/containerd.service.d
-cat <<EOF > /etc/systemd/system/containerd.service.d/11-exec_config.conf
-[Service]
-ExecStart=
-ExecStart=/usr/bin/containerd --config=/etc/containerd/config.toml
-EOF
-chmod 0644 /etc/systemd/system/containerd.service.d/11-exec_config.conf
+` + runtime.ProvisionScript() + `
 ` + writeFilesToDiskScript + `
 ` + writeUnitsToDiskScript + `
 grep -sq "^nfsd$" /etc/modules || echo "nfsd" >>/etc/modules
 modprobe nfsd
 nslookup $(hostname) || systemctl restart systemd-networkd
-
-systemctl daemon-reload
-systemctl enable containerd && systemctl restart containerd
-systemctl enable docker && systemctl restart docker
 `
 	for _, unit := range osc.Spec.Units {
 		script += fmt.Sprintf(`systemctl enable '%s' && systemctl restart --no-block '%s'
 `, unit.Name, unit.Name)
 	}
 
-	if osc.Spec.Type == memoryone.OSTypeMemoryOneGardenLinux {
-		return wrapIntoMemoryOneHeaderAndFooter(osc, script)
+	encoder, err := userDataEncoderForOSC(osc)
+	if err != nil {
+		return "", err
 	}
 
-	return script, nil
+	return encoder.Encode(osc, script)
 }
 
-func wrapIntoMemoryOneHeaderAndFooter(osc *extensionsv1alpha1.OperatingSystemConfig, in string) (string, error) {
-	config, err := memoryone.Configuration(osc)
+// gardenerNodeAgentBootstrapScript is the cloud-init rendered for Purpose=Provision when the
+// UseGardenerNodeAgent feature gate is enabled. It only installs the gardener-node-agent binary and
+// writes the OperatingSystemConfig resource to disk, the node-agent itself applies the Reconcile-purpose
+// units and files and enables/starts them, so no legacy bootstrap logic (containerd setup, docker,
+// systemd enable loop) is needed here anymore.
+//
+//go:embed scripts/gardener-node-agent-bootstrap.sh
+var gardenerNodeAgentBootstrapScript string
+
+func (a *actuator) handleProvisionOSCGardenerNodeAgent(_ context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) (string, error) {
+	oscRaw, err := oscToDiskScript(osc)
 	if err != nil {
 		return "", err
 	}
 
-	out := `Content-Type: multipart/mixed; boundary="==BOUNDARY=="
-MIME-Version: 1.0
---==BOUNDARY==
-Content-Type: text/x-vsmp; section=vsmp`
+	script := gardenerNodeAgentBootstrapScript + `
+` + oscRaw
 
-	if config != nil && config.SystemMemory != nil {
-		out += fmt.Sprintf(`
-system_memory=%s`, *config.SystemMemory)
-	}
-	if config != nil && config.MemoryTopology != nil {
-		out += fmt.Sprintf(`
-mem_topology=%s`, *config.MemoryTopology)
+	encoder, err := userDataEncoderForOSC(osc)
+	if err != nil {
+		return "", err
 	}
 
-	out += `
---==BOUNDARY==
-Content-Type: text/x-shellscript
-` + in + `
---==BOUNDARY==`
+	return encoder.Encode(osc, script)
+}
+
+// oscToDiskScript renders the shell snippet that writes the gardener-node-agent's own
+// OperatingSystemConfig resource to the well-known location the node-agent reads on startup.
+func oscToDiskScript(osc *extensionsv1alpha1.OperatingSystemConfig) (string, error) {
+	// Set the type fields explicitly, typed objects read through the client usually carry an empty TypeMeta.
+	oscWithKind := osc.DeepCopy()
+	oscWithKind.SetGroupVersionKind(extensionsv1alpha1.SchemeGroupVersion.WithKind("OperatingSystemConfig"))
 
-	return out, nil
+	oscYAML, err := yaml.Marshal(oscWithKind)
+	if err != nil {
+		return "", fmt.Errorf("failed marshalling OperatingSystemConfig %s: %w", osc.Name, err)
+	}
+
+	return fmt.Sprintf(`mkdir -p %[1]s
+cat <<'EOF' > %[2]s
+%[3]s
+EOF
+chmod 0600 %[2]s
+systemctl daemon-reload
+systemctl enable gardener-node-agent && systemctl restart gardener-node-agent
+`, gardenlinux.NodeAgentBaseDir, gardenlinux.NodeAgentOSCPath, string(oscYAML)), nil
 }
 
 var (
-	scriptContentInPlaceUpdate          []byte
-	scriptContentGFunctions             []byte
-	scriptContentKubeletCGroupDriver    []byte
-	scriptContentContainerdCGroupDriver []byte
+	scriptContentInPlaceUpdate                []byte
+	scriptContentGFunctions                   []byte
+	scriptContentKubeletCGroupDriver          []byte
+	scriptContentGLRollback                   []byte
+	scriptContentGardenLinuxUpdateHealthCheck []byte
 )
 
 func init() {
@@ -152,7 +173,9 @@ func init() {
 	utilruntime.Must(err)
 	scriptContentKubeletCGroupDriver, err = gardenlinux.Templates.ReadFile(filepath.Join("scripts", "kubelet_cgroup_driver.sh"))
 	utilruntime.Must(err)
-	scriptContentContainerdCGroupDriver, err = gardenlinux.Templates.ReadFile(filepath.Join("scripts", "containerd_cgroup_driver.sh"))
+	scriptContentGLRollback, err = gardenlinux.Templates.ReadFile(filepath.Join("scripts", "gl-rollback.sh"))
+	utilruntime.Must(err)
+	scriptContentGardenLinuxUpdateHealthCheck, err = gardenlinux.Templates.ReadFile(filepath.Join("scripts", "gardenlinux-update-healthcheck.sh"))
 	utilruntime.Must(err)
 }
 
@@ -168,9 +191,15 @@ func (a *actuator) handleReconcileOSC(osConfig *extensionsv1alpha1.OperatingSyst
 		Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: string(scriptContentInPlaceUpdate)}},
 		Permissions: &gardenlinux.ScriptPermissions,
 	})
+	settleWindowSeconds := int64(a.config.InPlaceUpdate.HealthCheckSettleWindow.Duration.Seconds())
+	maxRetries := ptr.Deref(a.config.InPlaceUpdate.MaxRetries, configv1alpha1.DefaultMaxRetries)
 	inPlaceUpdateConfig := &extensionsv1alpha1.InPlaceUpdateConfig{
-		OSUpdateCommand:     ptr.To(filePathOSUpdateScript),
-		OSUpdateCommandArgs: []string{ptr.Deref(osConfig.Spec.OSVersion, "")},
+		OSUpdateCommand: ptr.To(filePathOSUpdateScript),
+		OSUpdateCommandArgs: []string{
+			ptr.Deref(osConfig.Spec.OSVersion, ""),
+			strconv.FormatInt(settleWindowSeconds, 10),
+			strconv.Itoa(int(maxRetries)),
+		},
 	}
 
 	filePathFunctionsHelperScript := filepath.Join(gardenlinux.ScriptLocation, "g_functions.sh")
@@ -180,6 +209,35 @@ func (a *actuator) handleReconcileOSC(osConfig *extensionsv1alpha1.OperatingSyst
 		Permissions: &gardenlinux.ScriptPermissions,
 	})
 
+	// add scripts and unit for the automatic rollback health check
+	filePathGLRollbackScript := filepath.Join(gardenlinux.ScriptLocation, "gl-rollback.sh")
+	extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+		Path:        filePathGLRollbackScript,
+		Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: string(scriptContentGLRollback)}},
+		Permissions: &gardenlinux.ScriptPermissions,
+	})
+	filePathHealthCheckScript := filepath.Join(gardenlinux.ScriptLocation, "gardenlinux-update-healthcheck.sh")
+	extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+		Path:        filePathHealthCheckScript,
+		Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: string(scriptContentGardenLinuxUpdateHealthCheck)}},
+		Permissions: &gardenlinux.ScriptPermissions,
+	})
+	extensionUnits = append(extensionUnits, extensionsv1alpha1.Unit{
+		Name:    "gardenlinux-update-healthcheck.service",
+		Command: ptr.To(extensionsv1alpha1.CommandStart),
+		Enable:  ptr.To(true),
+		Content: ptr.To(`[Unit]
+Description=Roll back a failed Garden Linux in-place update
+After=kubelet.service
+[Service]
+Type=oneshot
+ExecStart=` + filePathHealthCheckScript + ` ` + strconv.FormatInt(settleWindowSeconds, 10) + ` ` + strconv.Itoa(int(maxRetries)) + `
+[Install]
+WantedBy=multi-user.target
+`),
+		FilePaths: []string{filePathFunctionsHelperScript, filePathGLRollbackScript, filePathHealthCheckScript},
+	})
+
 	// add scripts and dropins for kubelet
 	filePathKubeletCGroupDriverScript := filepath.Join(gardenlinux.ScriptLocation, "kubelet_cgroup_driver.sh")
 	extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
@@ -198,22 +256,27 @@ ExecStartPre=` + filePathKubeletCGroupDriverScript + `
 		FilePaths: []string{filePathFunctionsHelperScript, filePathKubeletCGroupDriverScript},
 	})
 
-	// add scripts and dropins for containerd
-	filePathContainerdCGroupDriverScript := filepath.Join(gardenlinux.ScriptLocation, "containerd_cgroup_driver.sh")
+	// add scripts and dropins for the configured container runtime
+	runtime, err := gardenlinux.ContainerRuntimeForOSC(osConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	runtimeUnitName, runtimeScriptName, runtimeScriptContent := runtime.CgroupDriverUnit()
+	filePathRuntimeCGroupDriverScript := filepath.Join(gardenlinux.ScriptLocation, runtimeScriptName)
 	extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
-		Path:        filePathContainerdCGroupDriverScript,
-		Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: string(scriptContentContainerdCGroupDriver)}},
+		Path:        filePathRuntimeCGroupDriverScript,
+		Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: string(runtimeScriptContent)}},
 		Permissions: &gardenlinux.ScriptPermissions,
 	})
 	extensionUnits = append(extensionUnits, extensionsv1alpha1.Unit{
-		Name: "containerd.service",
+		Name: runtimeUnitName,
 		DropIns: []extensionsv1alpha1.DropIn{{
 			Name: "10-configure-cgroup-driver.conf",
 			Content: `[Service]
-ExecStartPre=` + filePathContainerdCGroupDriverScript + `
+ExecStartPre=` + filePathRuntimeCGroupDriverScript + `
 `,
 		}},
-		FilePaths: []string{filePathFunctionsHelperScript, filePathContainerdCGroupDriverScript},
+		FilePaths: []string{filePathFunctionsHelperScript, filePathRuntimeCGroupDriverScript},
 	})
 
 	return extensionUnits, extensionFiles, inPlaceUpdateConfig, nil
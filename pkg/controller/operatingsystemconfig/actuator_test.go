@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1alpha1 "github.com/gardener/gardener-extension-os-gardenlinux/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/features"
+)
+
+var _ = Describe("Actuator", func() {
+	var (
+		ctx = context.Background()
+		a   *actuator
+		osc *extensionsv1alpha1.OperatingSystemConfig
+	)
+
+	BeforeEach(func() {
+		config := configv1alpha1.ControllerConfiguration{}
+		configv1alpha1.SetDefaults_ControllerConfiguration(&config)
+		a = &actuator{client: fakeclient.NewClientBuilder().Build(), config: config}
+		osc = &extensionsv1alpha1.OperatingSystemConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+				Units: []extensionsv1alpha1.Unit{{Name: "kubelet.service"}},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(features.DefaultFeatureGate.Set("UseGardenerNodeAgent=false")).To(Succeed())
+	})
+
+	Describe("#handleProvisionOSC", func() {
+		It("should render the legacy bootstrap script when the feature gate is disabled", func() {
+			userData, err := a.handleProvisionOSC(ctx, osc)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(userData).To(ContainSubstring("containerd config default"))
+			Expect(userData).To(ContainSubstring("systemctl enable docker"))
+			Expect(userData).To(ContainSubstring("systemctl enable 'kubelet.service'"))
+		})
+
+		It("should render the gardener-node-agent bootstrap script when the feature gate is enabled", func() {
+			Expect(features.DefaultFeatureGate.Set("UseGardenerNodeAgent=true")).To(Succeed())
+
+			userData, err := a.handleProvisionOSC(ctx, osc)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(userData).NotTo(ContainSubstring("containerd config default"))
+			Expect(userData).NotTo(ContainSubstring("systemctl enable docker"))
+			Expect(userData).To(ContainSubstring("gardener-node-agent"))
+			Expect(strings.Contains(userData, "kind: OperatingSystemConfig")).To(BeTrue())
+		})
+	})
+
+	Describe("#handleReconcileOSC", func() {
+		It("should return the extension units and files regardless of the feature gate", func() {
+			units, files, inPlaceUpdateConfig, err := a.handleReconcileOSC(osc)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(units).To(ContainElement(HaveField("Name", "kubelet.service")))
+			Expect(units).To(ContainElement(HaveField("Name", "containerd.service")))
+			Expect(files).NotTo(BeEmpty())
+			Expect(inPlaceUpdateConfig).NotTo(BeNil())
+		})
+
+		It("should install the rollback health check unit and thread the settle window and max retries into the scripts", func() {
+			units, _, inPlaceUpdateConfig, err := a.handleReconcileOSC(osc)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(units).To(ContainElement(HaveField("Name", "gardenlinux-update-healthcheck.service")))
+			Expect(inPlaceUpdateConfig.OSUpdateCommandArgs).To(HaveLen(3))
+			Expect(inPlaceUpdateConfig.OSUpdateCommandArgs[1]).To(Equal("300"))
+			Expect(inPlaceUpdateConfig.OSUpdateCommandArgs[2]).To(Equal("10"))
+		})
+	})
+})
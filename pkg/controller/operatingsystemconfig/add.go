@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+
+	extensionsoperatingsystemconfig "github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	configv1alpha1 "github.com/gardener/gardener-extension-os-gardenlinux/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/gardenlinux"
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/memoryone"
+)
+
+// ControllerName is the name of the operatingsystemconfig controller.
+const ControllerName = "operatingsystemconfig"
+
+// AddToManager registers the operatingsystemconfig controller with the given manager, handling both the
+// default Garden Linux OS type and the MemoryOne vSMP variant.
+func AddToManager(ctx context.Context, mgr manager.Manager, config configv1alpha1.ControllerConfiguration) error {
+	return extensionsoperatingsystemconfig.Add(mgr, extensionsoperatingsystemconfig.AddArgs{
+		Actuator:          NewActuator(mgr, config),
+		ControllerOptions: extensionsoperatingsystemconfig.DefaultAddOptions.Controller,
+		Types:             []string{gardenlinux.OSTypeGardenLinux, memoryone.OSTypeMemoryOneGardenLinux},
+	})
+}
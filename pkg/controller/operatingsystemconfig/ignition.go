@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/utils/ptr"
+)
+
+// ignitionVersion is the Ignition config spec version rendered by ignitionEncoder.
+const ignitionVersion = "3.4.0"
+
+// ignitionProvisionScriptPath is the path the rendered provisioning script is written to so the
+// generated oneshot unit can execute it.
+const ignitionProvisionScriptPath = "/opt/gardener/bin/provision.sh"
+
+// ignitionConfig is the subset of the Ignition v3 config spec rendered by ignitionEncoder.
+type ignitionConfig struct {
+	Ignition ignitionConfigVersion `json:"ignition"`
+	Storage  ignitionStorage       `json:"storage,omitempty"`
+	Systemd  ignitionSystemd       `json:"systemd,omitempty"`
+}
+
+type ignitionConfigVersion struct {
+	Version string `json:"version"`
+}
+
+type ignitionStorage struct {
+	Files []ignitionFile `json:"files,omitempty"`
+}
+
+type ignitionFile struct {
+	Path      string               `json:"path"`
+	Mode      int                  `json:"mode,omitempty"`
+	Overwrite *bool                `json:"overwrite,omitempty"`
+	Contents  ignitionFileContents `json:"contents"`
+}
+
+type ignitionFileContents struct {
+	Source string `json:"source"`
+}
+
+type ignitionSystemd struct {
+	Units []ignitionUnit `json:"units,omitempty"`
+}
+
+type ignitionUnit struct {
+	Name     string  `json:"name"`
+	Enabled  *bool   `json:"enabled,omitempty"`
+	Contents *string `json:"contents,omitempty"`
+}
+
+// ignitionEncoder renders the user data as an Ignition v3 configuration. `osc.Spec.Files` is translated
+// into `storage.files[]` and `osc.Spec.Units` into `systemd.units[]`; the provisioning script is written
+// to disk as an additional file and run by a oneshot unit, since Ignition itself has no notion of
+// inline shell provisioning.
+type ignitionEncoder struct{}
+
+func (ignitionEncoder) Encode(osc *extensionsv1alpha1.OperatingSystemConfig, script string) (string, error) {
+	config := ignitionConfig{Ignition: ignitionConfigVersion{Version: ignitionVersion}}
+
+	for _, file := range osc.Spec.Files {
+		if file.Content.Inline == nil {
+			continue
+		}
+		config.Storage.Files = append(config.Storage.Files, ignitionFileFromContent(file.Path, file.Permissions, file.Content.Inline.Data))
+	}
+	config.Storage.Files = append(config.Storage.Files, ignitionFileFromContent(ignitionProvisionScriptPath, ptr.To(int32(0755)), script))
+
+	for _, unit := range osc.Spec.Units {
+		config.Systemd.Units = append(config.Systemd.Units, ignitionUnit{
+			Name:     unit.Name,
+			Enabled:  unit.Enable,
+			Contents: unit.Content,
+		})
+	}
+	config.Systemd.Units = append(config.Systemd.Units, ignitionUnit{
+		Name:    "gardenlinux-provision.service",
+		Enabled: ptr.To(true),
+		Contents: ptr.To(`[Unit]
+Description=Garden Linux provisioning
+[Service]
+Type=oneshot
+ExecStart=` + ignitionProvisionScriptPath + `
+[Install]
+WantedBy=multi-user.target
+`),
+	})
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed marshalling Ignition config for OperatingSystemConfig %s: %w", osc.Name, err)
+	}
+
+	return string(out), nil
+}
+
+func ignitionFileFromContent(path string, permissions *int32, data string) ignitionFile {
+	mode := 0644
+	if permissions != nil {
+		mode = int(*permissions)
+	}
+
+	return ignitionFile{
+		Path:      path,
+		Mode:      mode,
+		Overwrite: ptr.To(true),
+		Contents:  ignitionFileContents{Source: "data:;base64," + base64.StdEncoding.EncodeToString([]byte(data))},
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/features"
+)
+
+func TestOperatingSystemConfig(t *testing.T) {
+	utilruntime.Must(features.RegisterFeatureGates())
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OperatingSystemConfig Controller Suite")
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/memoryone"
+)
+
+const (
+	// UserDataFormatRawShell renders the user data as the plain `#!/bin/bash` script
+	// handleProvisionOSC assembles. This is the default format and the one every Garden Linux image
+	// supports without any further tooling.
+	UserDataFormatRawShell = "raw-shell"
+	// UserDataFormatCloudInit renders the user data as a cloud-init multipart MIME document, wrapping
+	// the rendered script in a `text/x-shellscript` part next to a `text/cloud-config` part so operators
+	// can inject `write_files`, `runcmd`, `bootcmd` and SSH keys without having to shell-escape them into
+	// the script itself.
+	UserDataFormatCloudInit = "cloud-init"
+	// UserDataFormatMemoryOne renders the user data as the MemoryOne vSMP multipart envelope.
+	UserDataFormatMemoryOne = "memoryone"
+	// UserDataFormatIgnition renders the user data as an Ignition v3 configuration, translating
+	// `osc.Spec.Files` into `storage.files[]` and `osc.Spec.Units` into `systemd.units[]`.
+	UserDataFormatIgnition = "ignition"
+)
+
+// UserDataEncoder renders the final user-data document handed to the cloud provider from the
+// provisioning script handleProvisionOSC assembles for Purpose=Provision.
+type UserDataEncoder interface {
+	// Encode wraps the given provisioning script, rendered for the given OperatingSystemConfig, into the
+	// user-data document format the encoder implements.
+	Encode(osc *extensionsv1alpha1.OperatingSystemConfig, script string) (string, error)
+}
+
+// userDataProviderConfig is the subset of the OperatingSystemConfig provider config that overrides the
+// user-data encoder selected for an OperatingSystemConfig.
+type userDataProviderConfig struct {
+	UserDataFormat *string `json:"userDataFormat,omitempty"`
+}
+
+// userDataEncoderForOSC determines the UserDataEncoder to use for the given OperatingSystemConfig. An
+// explicit `userDataFormat` provider config field takes precedence over the OS type-derived default,
+// which is the MemoryOne envelope for the MemoryOne vSMP type and the raw shell script for everything
+// else.
+func userDataEncoderForOSC(osc *extensionsv1alpha1.OperatingSystemConfig) (UserDataEncoder, error) {
+	if osc.Spec.ProviderConfig != nil {
+		config := &userDataProviderConfig{}
+		if err := json.Unmarshal(osc.Spec.ProviderConfig.Raw, config); err != nil {
+			return nil, fmt.Errorf("failed decoding provider config of OperatingSystemConfig %s: %w", osc.Name, err)
+		}
+		if config.UserDataFormat != nil {
+			return userDataEncoderByName(*config.UserDataFormat)
+		}
+	}
+
+	if osc.Spec.Type == memoryone.OSTypeMemoryOneGardenLinux {
+		return userDataEncoderByName(UserDataFormatMemoryOne)
+	}
+
+	return userDataEncoderByName(UserDataFormatRawShell)
+}
+
+func userDataEncoderByName(name string) (UserDataEncoder, error) {
+	switch name {
+	case UserDataFormatRawShell:
+		return rawShellEncoder{}, nil
+	case UserDataFormatCloudInit:
+		return cloudInitEncoder{}, nil
+	case UserDataFormatMemoryOne:
+		return memoryOneEncoder{}, nil
+	case UserDataFormatIgnition:
+		return ignitionEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown user-data format %q", name)
+	}
+}
+
+// rawShellEncoder passes the provisioning script through unchanged. It is the default encoder and the
+// one in use before the UserDataEncoder abstraction was introduced.
+type rawShellEncoder struct{}
+
+func (rawShellEncoder) Encode(_ *extensionsv1alpha1.OperatingSystemConfig, script string) (string, error) {
+	return script, nil
+}
+
+// cloudInitEncoder wraps the provisioning script into a cloud-init multipart MIME document consisting of
+// an (initially empty) `text/cloud-config` part and a `text/x-shellscript` part carrying the script.
+// Operators can merge additional cloud-config directives (write_files, runcmd, bootcmd, ssh_authorized_keys,
+// ...) into the first part via their own cloud-init merge rules without touching the generated script.
+type cloudInitEncoder struct{}
+
+func (cloudInitEncoder) Encode(_ *extensionsv1alpha1.OperatingSystemConfig, script string) (string, error) {
+	return `Content-Type: multipart/mixed; boundary="==BOUNDARY=="
+MIME-Version: 1.0
+
+--==BOUNDARY==
+Content-Type: text/cloud-config; charset="us-ascii"
+
+#cloud-config
+--==BOUNDARY==
+Content-Type: text/x-shellscript; charset="us-ascii"
+
+` + script + `
+--==BOUNDARY==--
+`, nil
+}
+
+// memoryOneEncoder wraps the provisioning script into the MemoryOne vSMP multipart envelope.
+type memoryOneEncoder struct{}
+
+func (memoryOneEncoder) Encode(osc *extensionsv1alpha1.OperatingSystemConfig, script string) (string, error) {
+	return wrapIntoMemoryOneHeaderAndFooter(osc, script)
+}
+
+func wrapIntoMemoryOneHeaderAndFooter(osc *extensionsv1alpha1.OperatingSystemConfig, in string) (string, error) {
+	config, err := memoryone.Configuration(osc)
+	if err != nil {
+		return "", err
+	}
+
+	out := `Content-Type: multipart/mixed; boundary="==BOUNDARY=="
+MIME-Version: 1.0
+--==BOUNDARY==
+Content-Type: text/x-vsmp; section=vsmp`
+
+	if config != nil && config.SystemMemory != nil {
+		out += fmt.Sprintf(`
+system_memory=%s`, *config.SystemMemory)
+	}
+	if config != nil && config.MemoryTopology != nil {
+		out += fmt.Sprintf(`
+mem_topology=%s`, *config.MemoryTopology)
+	}
+
+	out += `
+--==BOUNDARY==
+Content-Type: text/x-shellscript
+` + in + `
+--==BOUNDARY==`
+
+	return out, nil
+}
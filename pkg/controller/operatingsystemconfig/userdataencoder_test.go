@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"os"
+	"path/filepath"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-gardenlinux/pkg/memoryone"
+)
+
+var _ = Describe("UserDataEncoder", func() {
+	var (
+		osc    *extensionsv1alpha1.OperatingSystemConfig
+		script string
+	)
+
+	BeforeEach(func() {
+		osc = &extensionsv1alpha1.OperatingSystemConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+				Files: []extensionsv1alpha1.File{{
+					Path:        "/etc/example.conf",
+					Permissions: ptr.To(int32(0644)),
+					Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "example contents\n"}},
+				}},
+				Units: []extensionsv1alpha1.Unit{{
+					Name:    "example.service",
+					Enable:  ptr.To(true),
+					Content: ptr.To("[Unit]\nDescription=Example\n"),
+				}},
+			},
+		}
+		script = "#!/bin/bash\necho hello\n"
+	})
+
+	DescribeTable("encoding against the golden file",
+		func(encoder UserDataEncoder, goldenFile string) {
+			expected, err := os.ReadFile(filepath.Join("testdata", goldenFile))
+			Expect(err).NotTo(HaveOccurred())
+
+			actual, err := encoder.Encode(osc, script)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(string(expected)))
+		},
+
+		Entry("raw shell", rawShellEncoder{}, "rawshell.golden"),
+		Entry("cloud-init", cloudInitEncoder{}, "cloudinit.golden"),
+		Entry("memoryone", memoryOneEncoder{}, "memoryone.golden"),
+		Entry("ignition", ignitionEncoder{}, "ignition.golden"),
+	)
+
+	Describe("#userDataEncoderForOSC", func() {
+		It("should default to the raw shell encoder", func() {
+			encoder, err := userDataEncoderForOSC(osc)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoder).To(Equal(rawShellEncoder{}))
+		})
+
+		It("should default to the memoryone encoder for the MemoryOne OS type", func() {
+			osc.Spec.Type = memoryone.OSTypeMemoryOneGardenLinux
+
+			encoder, err := userDataEncoderForOSC(osc)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoder).To(Equal(memoryOneEncoder{}))
+		})
+
+		It("should prefer the provider config override over the OS type default", func() {
+			osc.Spec.Type = memoryone.OSTypeMemoryOneGardenLinux
+			osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"userDataFormat":"ignition"}`)}
+
+			encoder, err := userDataEncoderForOSC(osc)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoder).To(Equal(ignitionEncoder{}))
+		})
+
+		It("should return an error for an unknown user-data format", func() {
+			osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"userDataFormat":"unknown"}`)}
+
+			_, err := userDataEncoderForOSC(osc)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package features contains the feature gates of the gardener-extension-os-gardenlinux.
+package features
+
+import (
+	"k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// UseGardenerNodeAgent enables the usage of the gardener-node-agent OSC contract instead of the
+	// legacy cloud-config-downloader for Garden Linux worker nodes. When enabled, the operating system
+	// config rendered for `Purpose=Provision` only bootstraps the gardener-node-agent, which then takes
+	// over applying the `Purpose=Reconcile` units and files itself.
+	UseGardenerNodeAgent featuregate.Feature = "UseGardenerNodeAgent"
+)
+
+// DefaultFeatureGate is the feature gate used by the gardener-extension-os-gardenlinux.
+var DefaultFeatureGate = feature.DefaultMutableFeatureGate
+
+var defaultFeatures = map[featuregate.Feature]featuregate.FeatureSpec{
+	UseGardenerNodeAgent: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// RegisterFeatureGates registers the feature gates of gardener-extension-os-gardenlinux into the
+// DefaultFeatureGate.
+func RegisterFeatureGates() error {
+	return DefaultFeatureGate.Add(defaultFeatures)
+}
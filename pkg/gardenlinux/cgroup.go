@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardenlinux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// CgroupVersion is the cgroup hierarchy mounted on a node.
+type CgroupVersion string
+
+const (
+	// CgroupV1 is the legacy, per-controller cgroup hierarchy.
+	CgroupV1 CgroupVersion = "v1"
+	// CgroupV2 is the unified cgroup hierarchy.
+	CgroupV2 CgroupVersion = "v2"
+)
+
+const (
+	cgroupV2ControllersFile = "sys/fs/cgroup/cgroup.controllers"
+	cgroupV2CPUMaxFile      = "sys/fs/cgroup/cpu.max"
+	cgroupV1CPUQuotaFile    = "sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupV1CPUPeriodFile   = "sys/fs/cgroup/cpu/cpu.cfs_period_us"
+	systemdRunDir           = "run/systemd/system"
+)
+
+// DetectCgroupVersion determines whether the unified (v2) or legacy (v1) cgroup hierarchy is mounted
+// under rootDir. Cgroup v2 always exposes a `cgroup.controllers` file at the root of the hierarchy,
+// cgroup v1 never does, so its presence is sufficient to tell them apart. rootDir is "/" on a real node
+// and a synthetic directory in tests.
+func DetectCgroupVersion(rootDir string) CgroupVersion {
+	if _, err := os.Stat(filepath.Join(rootDir, cgroupV2ControllersFile)); err == nil {
+		return CgroupV2
+	}
+
+	return CgroupV1
+}
+
+// UsesSystemdCgroupDriver reports whether containerd's `SystemdCgroup` option should be forced to true.
+// This is only safe when systemd is the running init system and the unified (v2) cgroup hierarchy is
+// mounted; on cgroup v1, or under a non-systemd init, containerd must keep using the cgroupfs driver.
+func UsesSystemdCgroupDriver(rootDir string) bool {
+	if DetectCgroupVersion(rootDir) != CgroupV2 {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(rootDir, systemdRunDir))
+	return err == nil
+}
+
+// EffectiveCPUQuota returns the number of CPUs effectively available to the current cgroup under
+// rootDir, derived from the container-imposed CPU quota as ceil(quota/period). If no quota is
+// configured (cgroup v2 "max", or a missing/non-positive v1 quota), it returns runtime.NumCPU().
+func EffectiveCPUQuota(rootDir string) (int, error) {
+	quota, period, ok, err := readCPUQuota(rootDir)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return runtime.NumCPU(), nil
+	}
+
+	return int((quota + period - 1) / period), nil
+}
+
+func readCPUQuota(rootDir string) (quota, period int64, ok bool, err error) {
+	switch DetectCgroupVersion(rootDir) {
+	case CgroupV2:
+		return readCPUMaxV2(filepath.Join(rootDir, cgroupV2CPUMaxFile))
+	default:
+		return readCPUQuotaV1(
+			filepath.Join(rootDir, cgroupV1CPUQuotaFile),
+			filepath.Join(rootDir, cgroupV1CPUPeriodFile),
+		)
+	}
+}
+
+func readCPUMaxV2(path string) (quota, period int64, ok bool, err error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- rootDir is controlled by the caller
+	if os.IsNotExist(err) {
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed reading %s: %w", path, err)
+	}
+
+	fields := strings.Fields(string(raw))
+	if len(fields) != 2 {
+		return 0, 0, false, fmt.Errorf("unexpected format of %s: %q", path, raw)
+	}
+	if fields[0] == "max" {
+		return 0, 0, false, nil
+	}
+
+	quota, err = strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed parsing quota in %s: %w", path, err)
+	}
+	period, err = strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed parsing period in %s: %w", path, err)
+	}
+
+	return quota, period, quota > 0 && period > 0, nil
+}
+
+func readCPUQuotaV1(quotaPath, periodPath string) (quota, period int64, ok bool, err error) {
+	quota, found, err := readInt64(quotaPath)
+	if err != nil || !found {
+		return 0, 0, false, err
+	}
+	period, found, err = readInt64(periodPath)
+	if err != nil || !found {
+		return 0, 0, false, err
+	}
+
+	return quota, period, quota > 0 && period > 0, nil
+}
+
+func readInt64(path string) (value int64, found bool, err error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- rootDir is controlled by the caller
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed reading %s: %w", path, err)
+	}
+
+	value, err = strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed parsing %s: %w", path, err)
+	}
+
+	return value, true, nil
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardenlinux_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gardener/gardener-extension-os-gardenlinux/pkg/gardenlinux"
+)
+
+// writeCgroupTree creates the given files (relative to "sys/fs/cgroup") with the given content under a
+// fresh temporary directory and returns the directory to be used as rootDir.
+func writeCgroupTree(files map[string]string) string {
+	root := GinkgoT().TempDir()
+
+	for relPath, content := range files {
+		path := filepath.Join(root, "sys", "fs", "cgroup", relPath)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	return root
+}
+
+var _ = Describe("cgroup", func() {
+	Describe("#DetectCgroupVersion", func() {
+		It("should detect cgroup v2 when cgroup.controllers exists", func() {
+			root := writeCgroupTree(map[string]string{"cgroup.controllers": "cpu memory"})
+			Expect(DetectCgroupVersion(root)).To(Equal(CgroupV2))
+		})
+
+		It("should detect cgroup v1 when cgroup.controllers is absent", func() {
+			root := writeCgroupTree(map[string]string{"cpu/cpu.cfs_quota_us": "-1"})
+			Expect(DetectCgroupVersion(root)).To(Equal(CgroupV1))
+		})
+	})
+
+	Describe("#EffectiveCPUQuota", func() {
+		It("should derive the effective CPU count from cpu.max on cgroup v2", func() {
+			root := writeCgroupTree(map[string]string{
+				"cgroup.controllers": "cpu memory",
+				"cpu.max":            "150000 100000",
+			})
+
+			Expect(EffectiveCPUQuota(root)).To(Equal(2))
+		})
+
+		It("should fall back to runtime.NumCPU() when cpu.max is unlimited on cgroup v2", func() {
+			root := writeCgroupTree(map[string]string{
+				"cgroup.controllers": "cpu memory",
+				"cpu.max":            "max 100000",
+			})
+
+			Expect(EffectiveCPUQuota(root)).To(Equal(runtime.NumCPU()))
+		})
+
+		It("should derive the effective CPU count from cfs_quota_us/cfs_period_us on cgroup v1", func() {
+			root := writeCgroupTree(map[string]string{
+				"cpu/cpu.cfs_quota_us":  "250000",
+				"cpu/cpu.cfs_period_us": "100000",
+			})
+
+			Expect(EffectiveCPUQuota(root)).To(Equal(3))
+		})
+
+		It("should fall back to runtime.NumCPU() when no quota is set on cgroup v1", func() {
+			root := writeCgroupTree(map[string]string{
+				"cpu/cpu.cfs_quota_us":  "-1",
+				"cpu/cpu.cfs_period_us": "100000",
+			})
+
+			Expect(EffectiveCPUQuota(root)).To(Equal(runtime.NumCPU()))
+		})
+	})
+
+	Describe("#UsesSystemdCgroupDriver", func() {
+		It("should be true when cgroup v2 is mounted and systemd is the init system", func() {
+			root := writeCgroupTree(map[string]string{"cgroup.controllers": "cpu memory"})
+			Expect(os.MkdirAll(filepath.Join(root, "run", "systemd", "system"), 0755)).To(Succeed())
+
+			Expect(UsesSystemdCgroupDriver(root)).To(BeTrue())
+		})
+
+		It("should be false on cgroup v1 even when systemd is the init system", func() {
+			root := writeCgroupTree(map[string]string{"cpu/cpu.cfs_quota_us": "-1"})
+			Expect(os.MkdirAll(filepath.Join(root, "run", "systemd", "system"), 0755)).To(Succeed())
+
+			Expect(UsesSystemdCgroupDriver(root)).To(BeFalse())
+		})
+
+		It("should be false when systemd is not the init system", func() {
+			root := writeCgroupTree(map[string]string{"cgroup.controllers": "cpu memory"})
+
+			Expect(UsesSystemdCgroupDriver(root)).To(BeFalse())
+		})
+	})
+})
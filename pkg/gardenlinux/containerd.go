@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardenlinux
+
+import _ "embed"
+
+//go:embed scripts/containerd_cgroup_driver.sh
+var containerdCgroupDriverScript []byte
+
+// containerdRuntime runs containerd with Docker installed alongside it, matching the historical default
+// behaviour of this extension.
+type containerdRuntime struct{}
+
+func (containerdRuntime) Name() string {
+	return ContainerRuntimeContainerd
+}
+
+func (containerdRuntime) ProvisionScript() string {
+	return containerdProvisionScript() + `systemctl enable docker && systemctl restart docker
+`
+}
+
+func (containerdRuntime) CgroupDriverUnit() (string, string, []byte) {
+	return "containerd.service", "containerd_cgroup_driver.sh", containerdCgroupDriverScript
+}
+
+// containerdProvisionScript returns the shell snippet shared by every backend that runs containerd,
+// configuring it with the default config and enabling/restarting the containerd.service unit. Backends
+// append whatever else they additionally need to enable (e.g. Docker) after this snippet.
+func containerdProvisionScript() string {
+	return `if [ ! -s /etc/containerd/config.toml ]; then
+  mkdir -p /etc/containerd/
+  containerd config default > /etc/containerd/config.toml
+  chmod 0644 /etc/containerd/config.toml
+fi
+
+mkdir -p /etc/systemd/system/containerd.service.d
+cat <<EOF > /etc/systemd/system/containerd.service.d/11-exec_config.conf
+[Service]
+ExecStart=
+ExecStart=/usr/bin/containerd --config=/etc/containerd/config.toml
+EOF
+chmod 0644 /etc/systemd/system/containerd.service.d/11-exec_config.conf
+
+systemctl daemon-reload
+systemctl enable containerd && systemctl restart containerd
+`
+}
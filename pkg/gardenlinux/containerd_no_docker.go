@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardenlinux
+
+// containerdNoDockerRuntime runs containerd without installing or enabling Docker, for Garden Linux
+// images that don't ship the Docker package.
+type containerdNoDockerRuntime struct{}
+
+func (containerdNoDockerRuntime) Name() string {
+	return ContainerRuntimeContainerdNoDocker
+}
+
+func (containerdNoDockerRuntime) ProvisionScript() string {
+	return containerdProvisionScript()
+}
+
+func (containerdNoDockerRuntime) CgroupDriverUnit() (string, string, []byte) {
+	return "containerd.service", "containerd_cgroup_driver.sh", containerdCgroupDriverScript
+}
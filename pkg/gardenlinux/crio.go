@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardenlinux
+
+import _ "embed"
+
+//go:embed scripts/crio_cgroup_driver.sh
+var crioCgroupDriverScript []byte
+
+// crioRuntime runs CRI-O as the container runtime instead of containerd.
+type crioRuntime struct{}
+
+func (crioRuntime) Name() string {
+	return ContainerRuntimeCRIO
+}
+
+func (crioRuntime) ProvisionScript() string {
+	return `mkdir -p /etc/crio/crio.conf.d
+systemctl daemon-reload
+systemctl enable crio && systemctl restart crio
+`
+}
+
+func (crioRuntime) CgroupDriverUnit() (string, string, []byte) {
+	return "crio.service", "crio_cgroup_driver.sh", crioCgroupDriverScript
+}
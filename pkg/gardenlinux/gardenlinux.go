@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gardenlinux bundles the scripts and constants shared by the operatingsystemconfig
+// controller when rendering the operating system config for Garden Linux worker nodes.
+package gardenlinux
+
+import (
+	"embed"
+)
+
+//go:embed scripts
+var Templates embed.FS
+
+// ScriptLocation is the directory on the worker node that the extension-managed scripts are written to.
+const ScriptLocation = "/opt/gardener/bin"
+
+// ScriptPermissions are the file permissions used for the extension-managed scripts.
+var ScriptPermissions = int32(0755)
+
+// OSTypeGardenLinux is the OperatingSystemConfig type used for the default Garden Linux variant.
+const OSTypeGardenLinux = "gardenlinux"
+
+const (
+	// NodeAgentBaseDir is the directory the gardener-node-agent reads its configuration from.
+	NodeAgentBaseDir = "/var/lib/gardener-node-agent"
+	// NodeAgentOSCPath is the path the gardener-node-agent reads its OperatingSystemConfig resource from.
+	NodeAgentOSCPath = NodeAgentBaseDir + "/osc.yaml"
+)
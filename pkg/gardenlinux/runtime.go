@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardenlinux
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+)
+
+const (
+	// ContainerRuntimeContainerd selects containerd with Docker still installed alongside it. This is the
+	// default backend, kept for backwards compatibility with existing clusters.
+	ContainerRuntimeContainerd = "containerd"
+	// ContainerRuntimeContainerdNoDocker selects containerd without Docker, for Garden Linux images that
+	// don't ship the Docker package.
+	ContainerRuntimeContainerdNoDocker = "containerd-no-docker"
+	// ContainerRuntimeCRIO selects CRI-O as the container runtime.
+	ContainerRuntimeCRIO = "crio"
+)
+
+// ContainerRuntime renders the provisioning snippet and reconcile-time cgroup-driver drop-in for a
+// specific container runtime backend supported by Garden Linux.
+type ContainerRuntime interface {
+	// Name returns the container runtime's identifier, as used in the `osc.Spec.Type` suffix and the
+	// provider config.
+	Name() string
+	// ProvisionScript returns the shell snippet that handleProvisionOSC embeds to set up and enable the
+	// container runtime.
+	ProvisionScript() string
+	// CgroupDriverUnit returns the name of the systemd unit the cgroup-driver drop-in is attached to, the
+	// file name the drop-in script is written under, and the drop-in script's content.
+	CgroupDriverUnit() (unitName, scriptName string, scriptContent []byte)
+}
+
+// providerConfig is the subset of the OperatingSystemConfig provider config that configures the
+// container runtime backend.
+type providerConfig struct {
+	ContainerRuntime *string `json:"containerRuntime,omitempty"`
+}
+
+// ContainerRuntimeForOSC determines the ContainerRuntime backend to use for the given
+// OperatingSystemConfig. An explicit `containerRuntime` provider config field takes precedence over a
+// `-<runtime>` suffix on `osc.Spec.Type`, which in turn takes precedence over the containerd-with-Docker
+// default used by clusters that don't request a specific backend.
+func ContainerRuntimeForOSC(osc *extensionsv1alpha1.OperatingSystemConfig) (ContainerRuntime, error) {
+	if osc.Spec.ProviderConfig != nil {
+		config := &providerConfig{}
+		if err := json.Unmarshal(osc.Spec.ProviderConfig.Raw, config); err != nil {
+			return nil, fmt.Errorf("failed decoding provider config of OperatingSystemConfig %s: %w", osc.Name, err)
+		}
+		if config.ContainerRuntime != nil {
+			return containerRuntimeByName(*config.ContainerRuntime)
+		}
+	}
+
+	for _, name := range []string{ContainerRuntimeCRIO, ContainerRuntimeContainerdNoDocker} {
+		if strings.HasSuffix(osc.Spec.Type, "-"+name) {
+			return containerRuntimeByName(name)
+		}
+	}
+
+	return containerRuntimeByName(ContainerRuntimeContainerd)
+}
+
+func containerRuntimeByName(name string) (ContainerRuntime, error) {
+	switch name {
+	case ContainerRuntimeContainerd:
+		return containerdRuntime{}, nil
+	case ContainerRuntimeContainerdNoDocker:
+		return containerdNoDockerRuntime{}, nil
+	case ContainerRuntimeCRIO:
+		return crioRuntime{}, nil
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q", name)
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gardenlinux_test
+
+import (
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/gardener/gardener-extension-os-gardenlinux/pkg/gardenlinux"
+)
+
+var _ = Describe("ContainerRuntime", func() {
+	Describe("#ContainerRuntimeForOSC", func() {
+		DescribeTable("selecting the backend",
+			func(osc *extensionsv1alpha1.OperatingSystemConfig, expectedName string) {
+				containerRuntime, err := ContainerRuntimeForOSC(osc)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(containerRuntime.Name()).To(Equal(expectedName))
+			},
+
+			Entry("defaults to containerd with Docker", &extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{},
+			}, ContainerRuntimeContainerd),
+
+			Entry("derives crio from the type suffix", &extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "gardenlinux-crio"}},
+			}, ContainerRuntimeCRIO),
+
+			Entry("derives containerd-no-docker from the type suffix", &extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "gardenlinux-containerd-no-docker"}},
+			}, ContainerRuntimeContainerdNoDocker),
+
+			Entry("prefers the provider config over the type suffix", &extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{
+						Type:           "gardenlinux-crio",
+						ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"containerRuntime":"containerd"}`)},
+					},
+				},
+			}, ContainerRuntimeContainerd),
+		)
+
+		It("should return an error for an unknown container runtime", func() {
+			_, err := ContainerRuntimeForOSC(&extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{
+						ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"containerRuntime":"unknown"}`)},
+					},
+				},
+			})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	DescribeTable("ProvisionScript and CgroupDriverUnit per backend",
+		func(containerRuntime ContainerRuntime, expectedUnit string, mustContain, mustNotContain []string) {
+			unitName, scriptName, scriptContent := containerRuntime.CgroupDriverUnit()
+			Expect(unitName).To(Equal(expectedUnit))
+			Expect(scriptName).NotTo(BeEmpty())
+			Expect(scriptContent).NotTo(BeEmpty())
+
+			script := containerRuntime.ProvisionScript()
+			for _, s := range mustContain {
+				Expect(script).To(ContainSubstring(s))
+			}
+			for _, s := range mustNotContain {
+				Expect(script).NotTo(ContainSubstring(s))
+			}
+		},
+
+		Entry("containerd", containerdRuntimeForTest(), "containerd.service",
+			[]string{"containerd config default", "systemctl enable docker"}, nil),
+		Entry("containerd-no-docker", containerdNoDockerRuntimeForTest(), "containerd.service",
+			[]string{"containerd config default"}, []string{"systemctl enable docker"}),
+		Entry("crio", crioRuntimeForTest(), "crio.service",
+			[]string{"systemctl enable crio"}, []string{"containerd config default"}),
+	)
+})
+
+func containerdRuntimeForTest() ContainerRuntime {
+	runtime, err := ContainerRuntimeForOSC(&extensionsv1alpha1.OperatingSystemConfig{})
+	Expect(err).NotTo(HaveOccurred())
+	return runtime
+}
+
+func containerdNoDockerRuntimeForTest() ContainerRuntime {
+	runtime, err := ContainerRuntimeForOSC(&extensionsv1alpha1.OperatingSystemConfig{
+		Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+			DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "gardenlinux-containerd-no-docker"},
+		},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return runtime
+}
+
+func crioRuntimeForTest() ContainerRuntime {
+	runtime, err := ContainerRuntimeForOSC(&extensionsv1alpha1.OperatingSystemConfig{
+		Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+			DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "gardenlinux-crio"},
+		},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return runtime
+}
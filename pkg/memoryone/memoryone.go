@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package memoryone contains helpers for rendering the user-data envelope required by the
+// MemoryOne vSMP variant of Garden Linux.
+package memoryone
+
+import (
+	"encoding/json"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+)
+
+// OSTypeMemoryOneGardenLinux is the OperatingSystemConfig type used for the MemoryOne vSMP variant
+// of Garden Linux.
+const OSTypeMemoryOneGardenLinux = "memoryone-gardenlinux"
+
+// Config contains the provider-config options that can be set for the MemoryOne vSMP variant.
+type Config struct {
+	// SystemMemory configures the `system_memory` vSMP parameter.
+	SystemMemory *string `json:"systemMemory,omitempty"`
+	// MemoryTopology configures the `mem_topology` vSMP parameter.
+	MemoryTopology *string `json:"memTopology,omitempty"`
+}
+
+// Configuration extracts the MemoryOne-specific provider config from the given OperatingSystemConfig, if any.
+func Configuration(osc *extensionsv1alpha1.OperatingSystemConfig) (*Config, error) {
+	if osc.Spec.ProviderConfig == nil {
+		return nil, nil
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(osc.Spec.ProviderConfig.Raw, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}